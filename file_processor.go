@@ -3,14 +3,94 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 )
 
+// errorSearcher 是扫描流水线实际依赖的最小接口；*ErrorSearcher 满足这个接口，
+// 生产代码中始终用它。把依赖收窄到接口是为了能在测试里传入假实现，
+// 而不必构造真正的 ErrorSearcher（及其内部的匹配规则）。
+type errorSearcher interface {
+	SearchErrors(content, path string) []ErrorInfo
+	SearchErrorsStream(r io.Reader, path string) ([]ErrorInfo, error)
+}
+
 // FileProcessor 负责处理文件列表和文件读取
 type FileProcessor struct {
 	baseDir string
+
+	// ExcludePatterns 是目录扫描时需要跳过的路径片段/glob，例如 "vendor/"、"_test.go"
+	ExcludePatterns []string
+	// IncludePatterns 非空时，目录扫描（ProcessDirectory、Watch）只处理命中其中至少一项的文件：
+	// 以 glob 匹配文件名，或作为子串出现在路径中；ProcessFileList 不使用它
+	IncludePatterns []string
+	// Concurrency 是并行扫描文件时使用的 worker 数量，小于等于 0 时默认为 runtime.NumCPU()
+	Concurrency int
+	// Format 显式指定输出格式（FormatMarkdown/FormatJSON/FormatCSV/FormatSARIF）；
+	// 留空时根据 outputPath 的扩展名推断，无法识别则回退到 Markdown
+	Format string
+	// StreamThreshold 是触发逐行流式扫描的文件大小（字节），超过该大小的文件
+	// 不再用 os.ReadFile 整体加载；小于等于 0 时使用 defaultStreamThreshold
+	StreamThreshold int64
+	// MaxRowsPerFile、MaxBytesPerFile 在 Format 为 Markdown 时启用滚动输出：
+	// 任意一项被突破就会另起一个带时间戳+序号后缀的分段文件，outputPath 本身
+	// 则改写为汇总所有分段的索引文件。两项都小于等于 0 时不滚动，行为与历史版本一致。
+	MaxRowsPerFile  int
+	MaxBytesPerFile int64
+}
+
+// defaultStreamThreshold 是 StreamThreshold 未设置时使用的默认阈值
+const defaultStreamThreshold int64 = 4 * 1024 * 1024
+
+// streamThreshold 返回生效的流式扫描阈值
+func (fp *FileProcessor) streamThreshold() int64 {
+	if fp.StreamThreshold > 0 {
+		return fp.StreamThreshold
+	}
+	return defaultStreamThreshold
+}
+
+// searchFile 读取并搜索单个文件，文件大小超过 streamThreshold 时走逐行
+// 流式扫描路径，否则沿用整体加载后一次性搜索的旧行为
+func (fp *FileProcessor) searchFile(fullFilePath, relPath string, searcher errorSearcher) ([]ErrorInfo, error) {
+	info, err := os.Stat(fullFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() <= fp.streamThreshold() {
+		content, err := fp.readFileContent(fullFilePath)
+		if err != nil {
+			return nil, err
+		}
+		return searcher.SearchErrors(content, relPath), nil
+	}
+
+	file, err := os.Open(fullFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return searcher.SearchErrorsStream(file, relPath)
+}
+
+// scanJob 是下发给 worker 的一个扫描任务，seq 记录其在原始文件列表中的顺序，
+// 以便结果可以在汇总阶段按原始顺序重新排列
+type scanJob struct {
+	seq     int
+	relPath string
+}
+
+// scanResult 是 worker 处理完一个 scanJob 后产出的结果
+type scanResult struct {
+	seq    int
+	errors []ErrorInfo
+	err    error
 }
 
 // NewFileProcessor 创建新的文件处理器
@@ -20,7 +100,9 @@ func NewFileProcessor(baseDir string) *FileProcessor {
 	}
 }
 
-// ProcessFileList 处理文件列表，搜索错误信息并写入输出文件
+// ProcessFileList 处理文件列表，搜索错误信息并写入输出文件。
+// 文件的读取与搜索通过一个固定数量的 worker 池并行完成，但输出顺序
+// 与 ErrorInfo.Index 的编号始终与文件列表中的原始顺序保持一致。
 func (fp *FileProcessor) ProcessFileList(fileListPath, outputPath string, searcher *ErrorSearcher) (int, error) {
 	// 读取文件列表
 	filePaths, err := fp.readFileList(fileListPath)
@@ -28,56 +110,116 @@ func (fp *FileProcessor) ProcessFileList(fileListPath, outputPath string, search
 		return 0, fmt.Errorf("读取文件列表失败: %w", err)
 	}
 
-	var allErrors []ErrorInfo
+	allErrors, err := fp.scanFiles(filePaths, searcher)
+	if err != nil {
+		return 0, err
+	}
+
 	errorCount := 0
+	for i := range allErrors {
+		errorCount++
+		allErrors[i].Index = errorCount
+	}
 
-	// 遍历文件列表中的每个文件
-	for _, relativeFilePath := range filePaths {
-		// 处理相对路径，确保去掉开头的 ./
-		filePath := strings.TrimPrefix(relativeFilePath, "./")
+	// 写入输出文件
+	if err := fp.writeOutputFile(outputPath, allErrors); err != nil {
+		return 0, fmt.Errorf("写入输出文件失败: %w", err)
+	}
+
+	return errorCount, nil
+}
 
-		// 构建完整路径
-		fullFilePath := filepath.Join(fp.baseDir, filePath)
+// scanFiles 用 producer/worker/collector 的流水线并行扫描 filePaths，
+// 并按照 filePaths 的原始顺序返回汇总后的 ErrorInfo
+func (fp *FileProcessor) scanFiles(filePaths []string, searcher errorSearcher) ([]ErrorInfo, error) {
+	concurrency := fp.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
 
-		// 检查文件是否存在
-		if _, err := os.Stat(fullFilePath); os.IsNotExist(err) {
-			fmt.Printf("文件不存在: %s\n", fullFilePath)
-			continue
-		}
+	jobs := make(chan scanJob, concurrency)
+	results := make(chan scanResult, concurrency)
 
-		// 只处理 .go 文件
-		if !strings.HasSuffix(strings.ToLower(filePath), ".go") {
-			continue
+	// producer：把文件列表中的每一项按序号送入 jobs 通道
+	go func() {
+		defer close(jobs)
+		for i, relativeFilePath := range filePaths {
+			jobs <- scanJob{seq: i, relPath: relativeFilePath}
 		}
+	}()
 
-		// 读取文件内容
-		content, err := fp.readFileContent(fullFilePath)
-		if err != nil {
-			fmt.Printf("读取文件时出错 %s: %v\n", relativeFilePath, err)
+	// worker 池：并行读取并搜索文件内容（大文件走 searcher.SearchErrorsStream）
+	var workers sync.WaitGroup
+	workers.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer workers.Done()
+			for job := range jobs {
+				results <- fp.scanOne(job, searcher)
+			}
+		}()
+	}
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	// collector：按 seq 缓冲结果，保证最终切片与原始文件顺序一致
+	buffered := make(map[int][]ErrorInfo, len(filePaths))
+	var firstErr error
+	for res := range results {
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = res.err
+			}
 			continue
 		}
+		buffered[res.seq] = res.errors
+	}
 
-		// 搜索错误信息
-		errors := searcher.SearchErrors(content, relativeFilePath)
+	if firstErr != nil {
+		return nil, firstErr
+	}
 
-		// 为每个错误分配索引
-		for i := range errors {
-			errorCount++
-			errors[i].Index = errorCount
-		}
+	var allErrors []ErrorInfo
+	for i := range filePaths {
+		allErrors = append(allErrors, buffered[i]...)
+	}
+	return allErrors, nil
+}
 
-		allErrors = append(allErrors, errors...)
+// scanOne 处理单个文件：校验路径，再经 searchFile 读取并搜索内容
+// （小文件调用 searcher.SearchErrors，大文件调用 searcher.SearchErrorsStream）
+func (fp *FileProcessor) scanOne(job scanJob, searcher errorSearcher) scanResult {
+	// 处理相对路径，确保去掉开头的 ./
+	filePath := strings.TrimPrefix(job.relPath, "./")
+
+	// 构建完整路径
+	fullFilePath := filepath.Join(fp.baseDir, filePath)
+
+	// 检查文件是否存在
+	if _, err := os.Stat(fullFilePath); os.IsNotExist(err) {
+		fmt.Printf("文件不存在: %s\n", fullFilePath)
+		return scanResult{seq: job.seq}
 	}
 
-	// 写入输出文件
-	if err := fp.writeOutputFile(outputPath, allErrors); err != nil {
-		return 0, fmt.Errorf("写入输出文件失败: %w", err)
+	// 只处理 .go 文件
+	if !strings.HasSuffix(strings.ToLower(filePath), ".go") {
+		return scanResult{seq: job.seq}
 	}
 
-	return errorCount, nil
+	// 读取文件内容并搜索错误信息（大文件走流式扫描）
+	errors, err := fp.searchFile(fullFilePath, job.relPath, searcher)
+	if err != nil {
+		return scanResult{seq: job.seq, err: fmt.Errorf("读取文件 %s 失败: %w", job.relPath, err)}
+	}
+
+	return scanResult{seq: job.seq, errors: errors}
 }
 
-// readFileList 读取文件列表
+// readFileList 读取文件列表，列表中的每一行既可以是具体的文件路径，
+// 也可以是 shell 风格的 glob（如 internal/**/*.go、cmd/*/main.go）
 func (fp *FileProcessor) readFileList(filePath string) ([]string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -89,9 +231,15 @@ func (fp *FileProcessor) readFileList(filePath string) ([]string, error) {
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
-		if line != "" {
-			filePaths = append(filePaths, line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		expanded, err := fp.expandGlob(line)
+		if err != nil {
+			return nil, fmt.Errorf("展开 glob 模式 %q 失败: %w", line, err)
 		}
+		filePaths = append(filePaths, expanded...)
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -101,6 +249,169 @@ func (fp *FileProcessor) readFileList(filePath string) ([]string, error) {
 	return filePaths, nil
 }
 
+// expandGlob 将单个列表条目展开为实际文件路径。不包含 glob 特殊字符的条目原样返回，
+// 以保持对旧的、纯文件路径列表文件的兼容。
+func (fp *FileProcessor) expandGlob(pattern string) ([]string, error) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+
+	if strings.Contains(pattern, "**") {
+		return fp.globDoubleStar(pattern)
+	}
+
+	fullPattern := filepath.Join(fp.baseDir, pattern)
+	matches, err := filepath.Glob(fullPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	relMatches := make([]string, 0, len(matches))
+	for _, m := range matches {
+		rel, err := filepath.Rel(fp.baseDir, m)
+		if err != nil {
+			return nil, err
+		}
+		relMatches = append(relMatches, rel)
+	}
+	return relMatches, nil
+}
+
+// globDoubleStar 实现一个简单的 "**" 匹配器：将模式按 "**" 拆分成前缀和后缀，
+// 对 baseDir 下的所有文件做前缀/后缀匹配，从而支持 internal/**/*.go 这类写法。
+func (fp *FileProcessor) globDoubleStar(pattern string) ([]string, error) {
+	parts := strings.SplitN(pattern, "**", 2)
+	prefix := strings.TrimSuffix(parts[0], "/")
+	suffix := strings.TrimPrefix(parts[1], "/")
+
+	var matches []string
+	walkRoot := filepath.Join(fp.baseDir, prefix)
+	err := filepath.WalkDir(walkRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(fp.baseDir, path)
+		if err != nil {
+			return err
+		}
+
+		relFromPrefix, err := filepath.Rel(walkRoot, path)
+		if err != nil {
+			return err
+		}
+
+		if suffix == "" {
+			matches = append(matches, rel)
+			return nil
+		}
+
+		ok, err := filepath.Match(suffix, filepath.Base(path))
+		if err != nil {
+			return err
+		}
+		if ok || strings.HasSuffix(relFromPrefix, suffix) {
+			matches = append(matches, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// ProcessDirectory 递归扫描 rootDir 下的所有 .go 文件，搜索错误信息并写入输出文件，
+// 从而不必预先生成文件列表即可对整个仓库进行扫描
+func (fp *FileProcessor) ProcessDirectory(rootDir, outputPath string, searcher *ErrorSearcher) (int, error) {
+	var allErrors []ErrorInfo
+	errorCount := 0
+
+	err := filepath.WalkDir(rootDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if fp.isExcluded(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(strings.ToLower(path), ".go") {
+			return nil
+		}
+
+		if fp.isExcluded(path) || !fp.isIncluded(path) {
+			return nil
+		}
+
+		relPath, relErr := filepath.Rel(rootDir, path)
+		if relErr != nil {
+			relPath = path
+		}
+
+		errors, err := fp.searchFile(path, relPath, searcher)
+		if err != nil {
+			fmt.Printf("读取文件时出错 %s: %v\n", relPath, err)
+			return nil
+		}
+
+		for i := range errors {
+			errorCount++
+			errors[i].Index = errorCount
+		}
+		allErrors = append(allErrors, errors...)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("扫描目录失败: %w", err)
+	}
+
+	if err := fp.writeOutputFile(outputPath, allErrors); err != nil {
+		return 0, fmt.Errorf("写入输出文件失败: %w", err)
+	}
+
+	return errorCount, nil
+}
+
+// isExcluded 判断路径是否命中 ExcludePatterns 中的任意一项（子串或 glob 均可）
+func (fp *FileProcessor) isExcluded(path string) bool {
+	for _, pattern := range fp.ExcludePatterns {
+		if strings.Contains(path, pattern) {
+			return true
+		}
+		if ok, err := filepath.Match(pattern, filepath.Base(path)); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isIncluded 判断路径是否满足 IncludePatterns；IncludePatterns 为空时视为全部放行
+func (fp *FileProcessor) isIncluded(path string) bool {
+	if len(fp.IncludePatterns) == 0 {
+		return true
+	}
+	for _, pattern := range fp.IncludePatterns {
+		if ok, err := filepath.Match(pattern, filepath.Base(path)); err == nil && ok {
+			return true
+		}
+		if strings.Contains(path, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 // readFileContent 读取文件内容
 func (fp *FileProcessor) readFileContent(filePath string) (string, error) {
 	content, err := os.ReadFile(filePath)
@@ -110,8 +421,20 @@ func (fp *FileProcessor) readFileContent(filePath string) (string, error) {
 	return string(content), nil
 }
 
-// writeOutputFile 写入 Markdown 格式的输出文件
+// writeOutputFile 按 fp.Format（或 outputPath 的扩展名）选择 Formatter 并写入输出文件；
+// 当格式为 Markdown 且设置了滚动阈值时，改为调用 writeRollingMarkdown
 func (fp *FileProcessor) writeOutputFile(outputPath string, errors []ErrorInfo) error {
+	format := detectFormat(fp.Format, outputPath)
+
+	if format == FormatMarkdown && fp.rollingEnabled() {
+		return fp.writeRollingMarkdown(outputPath, errors)
+	}
+
+	formatter, err := newFormatter(format)
+	if err != nil {
+		return err
+	}
+
 	file, err := os.Create(outputPath)
 	if err != nil {
 		return err
@@ -121,26 +444,5 @@ func (fp *FileProcessor) writeOutputFile(outputPath string, errors []ErrorInfo)
 	writer := bufio.NewWriter(file)
 	defer writer.Flush()
 
-	// 写入表头
-	header := "# 相关错误信息汇总\n\n"
-	header += "| 报错日志 | 文件路径 | 行号 |\n"
-	header += "| -------- | -------- | ---- |\n"
-
-	if _, err := writer.WriteString(header); err != nil {
-		return err
-	}
-
-	// 写入数据行
-	for _, errInfo := range errors {
-		// 对错误消息中的管道符进行处理，避免破坏表格格式
-		escapedErrorMessage := strings.ReplaceAll(errInfo.ErrorMessage, "|", "\\|")
-		line := fmt.Sprintf("| %s | %s | %d |\n", escapedErrorMessage, errInfo.FilePath, errInfo.LineNum)
-
-		if _, err := writer.WriteString(line); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return formatter.Write(writer, errors)
 }
-
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeSearcher 是测试用的 errorSearcher 假实现：按文件路径返回预先配置好的
+// 错误列表，并可以模拟某个文件流式扫描时失败，用来驱动 scanFiles 的
+// 排序与错误传播逻辑，而不必依赖真正的 ErrorSearcher 匹配规则。
+type fakeSearcher struct {
+	errorsByPath map[string][]ErrorInfo
+	failStream   map[string]bool
+}
+
+func (f *fakeSearcher) SearchErrors(content, path string) []ErrorInfo {
+	return f.errorsByPath[path]
+}
+
+func (f *fakeSearcher) SearchErrorsStream(r io.Reader, path string) ([]ErrorInfo, error) {
+	if f.failStream[path] {
+		return nil, errors.New("模拟的流式扫描失败")
+	}
+	return f.errorsByPath[path], nil
+}
+
+func writeTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	full := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanFilesPreservesOriginalOrder(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{"a.go", "b.go", "c.go", "d.go", "e.go"}
+	for _, n := range names {
+		writeTestFile(t, dir, n, "package main\n")
+	}
+
+	fp := NewFileProcessor(dir)
+	fp.Concurrency = 4
+
+	searcher := &fakeSearcher{errorsByPath: map[string][]ErrorInfo{}}
+	for _, n := range names {
+		searcher.errorsByPath[n] = []ErrorInfo{{ErrorMessage: "err-" + n, FilePath: n, LineNum: 1}}
+	}
+
+	errs, err := fp.scanFiles(names, searcher)
+	if err != nil {
+		t.Fatalf("scanFiles returned error: %v", err)
+	}
+
+	if len(errs) != len(names) {
+		t.Fatalf("expected %d errors, got %d", len(names), len(errs))
+	}
+	for i, n := range names {
+		if errs[i].FilePath != n {
+			t.Errorf("position %d: expected file %s, got %s (order not preserved despite concurrency)", i, n, errs[i].FilePath)
+		}
+	}
+}
+
+func TestScanFilesSurfacesWorkerErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "big.go", "package main\n")
+
+	fp := NewFileProcessor(dir)
+	fp.StreamThreshold = 1 // 强制走流式扫描路径，从而触发 failStream
+
+	searcher := &fakeSearcher{
+		errorsByPath: map[string][]ErrorInfo{},
+		failStream:   map[string]bool{"big.go": true},
+	}
+
+	if _, err := fp.scanFiles([]string{"big.go"}, searcher); err == nil {
+		t.Fatal("expected scanFiles to surface the worker's read/search error, got nil")
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Formatter 负责把一组 ErrorInfo 写成某种具体的输出格式
+type Formatter interface {
+	Write(w io.Writer, errs []ErrorInfo) error
+}
+
+// 支持的输出格式名，用于 FileProcessor.Format 以及按扩展名推断格式
+const (
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatSARIF    = "sarif"
+)
+
+// formatterForExt 按文件扩展名推断默认的输出格式
+var formatterForExt = map[string]string{
+	".md":    FormatMarkdown,
+	".json":  FormatJSON,
+	".csv":   FormatCSV,
+	".sarif": FormatSARIF,
+}
+
+// detectFormat 根据 FileProcessor.Format 或输出文件的扩展名确定最终使用的格式，
+// Format 未显式设置时回退到扩展名推断，两者都无法识别时默认使用 Markdown
+func detectFormat(explicitFormat, outputPath string) string {
+	if explicitFormat != "" {
+		return explicitFormat
+	}
+	if format, ok := formatterForExt[strings.ToLower(filepath.Ext(outputPath))]; ok {
+		return format
+	}
+	return FormatMarkdown
+}
+
+// newFormatter 根据格式名构造对应的 Formatter 实现
+func newFormatter(format string) (Formatter, error) {
+	switch format {
+	case FormatMarkdown:
+		return markdownFormatter{}, nil
+	case FormatJSON:
+		return jsonFormatter{}, nil
+	case FormatCSV:
+		return csvFormatter{}, nil
+	case FormatSARIF:
+		return sarifFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("不支持的输出格式: %s", format)
+	}
+}
+
+// markdownTableHeader 返回 Markdown 表格的标题与表头，rolling 模式下每个
+// 分段文件都需要重新写入一份
+func markdownTableHeader() string {
+	header := "# 相关错误信息汇总\n\n"
+	header += "| 报错日志 | 文件路径 | 行号 |\n"
+	header += "| -------- | -------- | ---- |\n"
+	return header
+}
+
+// markdownTableRow 把单条 ErrorInfo 渲染成一行 Markdown 表格数据
+func markdownTableRow(errInfo ErrorInfo) string {
+	// 对错误消息中的管道符进行处理，避免破坏表格格式
+	escapedErrorMessage := strings.ReplaceAll(errInfo.ErrorMessage, "|", "\\|")
+	return fmt.Sprintf("| %s | %s | %d |\n", escapedErrorMessage, errInfo.FilePath, errInfo.LineNum)
+}
+
+// markdownFormatter 输出与历史版本一致的 Markdown 表格
+type markdownFormatter struct{}
+
+func (markdownFormatter) Write(w io.Writer, errs []ErrorInfo) error {
+	if _, err := io.WriteString(w, markdownTableHeader()); err != nil {
+		return err
+	}
+
+	for _, errInfo := range errs {
+		if _, err := io.WriteString(w, markdownTableRow(errInfo)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonFormatter 把结果编码为 ErrorInfo 数组的 JSON
+type jsonFormatter struct{}
+
+func (jsonFormatter) Write(w io.Writer, errs []ErrorInfo) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if errs == nil {
+		errs = []ErrorInfo{}
+	}
+	return encoder.Encode(errs)
+}
+
+// csvFormatter 把结果编码为带表头的 CSV
+type csvFormatter struct{}
+
+func (csvFormatter) Write(w io.Writer, errs []ErrorInfo) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"序号", "报错日志", "文件路径", "行号"}); err != nil {
+		return err
+	}
+
+	for _, errInfo := range errs {
+		record := []string{
+			strconv.Itoa(errInfo.Index),
+			errInfo.ErrorMessage,
+			errInfo.FilePath,
+			strconv.Itoa(errInfo.LineNum),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return writer.Error()
+}
+
+// sarifFormatter 把结果编码为 SARIF 2.1.0，便于上传到 GitHub code scanning 等平台
+type sarifFormatter struct{}
+
+// sarifToolName/sarifToolVersion 标识本扫描器在 SARIF tool.driver 中的身份
+const (
+	sarifToolName    = "group4-error-scanner"
+	sarifToolVersion = "1.0.0"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func (sarifFormatter) Write(w io.Writer, errs []ErrorInfo) error {
+	results := make([]sarifResult, 0, len(errs))
+	for _, errInfo := range errs {
+		results = append(results, sarifResult{
+			RuleID:  "error-log-reference",
+			Message: sarifMessage{Text: errInfo.ErrorMessage},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: errInfo.FilePath},
+					Region:           sarifRegion{StartLine: errInfo.LineNum},
+				},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:    sarifToolName,
+				Version: sarifToolVersion,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
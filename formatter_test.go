@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func sampleErrors() []ErrorInfo {
+	return []ErrorInfo{
+		{Index: 1, ErrorMessage: "nil pointer | panic", FilePath: "a.go", LineNum: 10},
+		{Index: 2, ErrorMessage: "out of range", FilePath: "b.go", LineNum: 42},
+	}
+}
+
+func TestMarkdownFormatterEscapesPipesAndKeepsOrder(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (markdownFormatter{}).Write(&buf, sampleErrors()); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `nil pointer \| panic`) {
+		t.Errorf("expected pipe in error message to be escaped, got:\n%s", out)
+	}
+	if strings.Index(out, "a.go") > strings.Index(out, "b.go") {
+		t.Errorf("expected a.go row before b.go row, got:\n%s", out)
+	}
+}
+
+func TestJSONFormatterRoundTrips(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (jsonFormatter{}).Write(&buf, sampleErrors()); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	var got []ErrorInfo
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(got) != 2 || got[0].FilePath != "a.go" || got[1].LineNum != 42 {
+		t.Fatalf("unexpected decoded result: %+v", got)
+	}
+}
+
+func TestCSVFormatterHasHeaderAndRows(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (csvFormatter{}).Write(&buf, sampleErrors()); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 3 { // 表头 + 2 行数据
+		t.Fatalf("expected 3 records (header + 2 rows), got %d: %v", len(records), records)
+	}
+	if records[1][1] != "nil pointer | panic" || records[1][2] != "a.go" {
+		t.Fatalf("unexpected first data row: %v", records[1])
+	}
+}
+
+func TestSARIFFormatterMapsLocationsAndDriver(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (sarifFormatter{}).Write(&buf, sampleErrors()); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("output is not valid SARIF JSON: %v", err)
+	}
+
+	if log.Version != "2.1.0" {
+		t.Fatalf("expected SARIF version 2.1.0, got %s", log.Version)
+	}
+	if len(log.Runs) != 1 || log.Runs[0].Tool.Driver.Name != sarifToolName {
+		t.Fatalf("expected a single run with driver name %s, got %+v", sarifToolName, log.Runs)
+	}
+	if len(log.Runs[0].Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(log.Runs[0].Results))
+	}
+
+	first := log.Runs[0].Results[0]
+	loc := first.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "a.go" || loc.Region.StartLine != 10 {
+		t.Fatalf("unexpected location for first result: %+v", loc)
+	}
+}
+
+func TestDetectFormatPrefersExplicitOverExtension(t *testing.T) {
+	if got := detectFormat(FormatJSON, "out.md"); got != FormatJSON {
+		t.Fatalf("expected explicit format to win, got %s", got)
+	}
+	if got := detectFormat("", "out.csv"); got != FormatCSV {
+		t.Fatalf("expected .csv to infer csv format, got %s", got)
+	}
+	if got := detectFormat("", "out.unknown"); got != FormatMarkdown {
+		t.Fatalf("expected unknown extension to fall back to markdown, got %s", got)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import "testing"
+
+func TestExpandGlobPlainPathPassesThrough(t *testing.T) {
+	fp := NewFileProcessor(t.TempDir())
+
+	got, err := fp.expandGlob("cmd/main.go")
+	if err != nil {
+		t.Fatalf("expandGlob returned error: %v", err)
+	}
+	if len(got) != 1 || got[0] != "cmd/main.go" {
+		t.Fatalf("expected plain path to pass through unchanged, got %v", got)
+	}
+}
+
+func TestExpandGlobSingleStar(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "cmd/foo/main.go", "package main\n")
+	writeTestFile(t, dir, "cmd/bar/main.go", "package main\n")
+	writeTestFile(t, dir, "cmd/bar/helper.go", "package main\n")
+
+	fp := NewFileProcessor(dir)
+
+	got, err := fp.expandGlob("cmd/*/main.go")
+	if err != nil {
+		t.Fatalf("expandGlob returned error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 matches, got %v", got)
+	}
+}
+
+func TestExpandGlobDoubleStar(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "internal/a/x.go", "package main\n")
+	writeTestFile(t, dir, "internal/a/b/y.go", "package main\n")
+	writeTestFile(t, dir, "internal/a/b/y_test.go", "package main\n")
+
+	fp := NewFileProcessor(dir)
+
+	got, err := fp.expandGlob("internal/**/*.go")
+	if err != nil {
+		t.Fatalf("expandGlob returned error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 matches under internal/**/*.go, got %v", got)
+	}
+}
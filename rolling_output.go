@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// rollingTimestampLayout 用于分段文件名中的时间戳后缀
+const rollingTimestampLayout = "20060102_150405"
+
+// rollingEnabled 判断是否应当走滚动输出路径
+func (fp *FileProcessor) rollingEnabled() bool {
+	return fp.MaxRowsPerFile > 0 || fp.MaxBytesPerFile > 0
+}
+
+// rollingSegment 是一个滚动输出分段：对应磁盘上的一个 Markdown 文件及其承载的错误条目
+type rollingSegment struct {
+	path string
+	rows []ErrorInfo
+}
+
+// writeRollingMarkdown 把 errors 按 MaxRowsPerFile/MaxBytesPerFile 切分成多个
+// Markdown 分段文件，每个分段都重新写入表头，并在 outputPath 生成汇总索引
+func (fp *FileProcessor) writeRollingMarkdown(outputPath string, errors []ErrorInfo) error {
+	segments := fp.splitIntoSegments(outputPath, errors)
+
+	for _, seg := range segments {
+		if err := fp.writeMarkdownSegment(seg.path, seg.rows); err != nil {
+			return fmt.Errorf("写入分段文件 %s 失败: %w", seg.path, err)
+		}
+	}
+
+	return fp.writeRollingIndex(outputPath, segments)
+}
+
+// splitIntoSegments 按行数/字节数上限把 errors 分组，并为每组分配分段文件名
+func (fp *FileProcessor) splitIntoSegments(outputPath string, errors []ErrorInfo) []rollingSegment {
+	ext := filepath.Ext(outputPath)
+	if ext == "" {
+		ext = ".md"
+	}
+	base := strings.TrimSuffix(outputPath, ext)
+	timestamp := time.Now().Format(rollingTimestampLayout)
+	headerBytes := int64(len(markdownTableHeader()))
+
+	var segments []rollingSegment
+	var current []ErrorInfo
+	var currentBytes int64
+
+	newSegmentPath := func() string {
+		return fmt.Sprintf("%s_%s_%03d%s", base, timestamp, len(segments)+1, ext)
+	}
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		segments = append(segments, rollingSegment{path: newSegmentPath(), rows: current})
+		current = nil
+		currentBytes = 0
+	}
+
+	for _, errInfo := range errors {
+		rowBytes := int64(len(markdownTableRow(errInfo)))
+
+		exceedsRows := fp.MaxRowsPerFile > 0 && len(current) >= fp.MaxRowsPerFile
+		exceedsBytes := fp.MaxBytesPerFile > 0 && len(current) > 0 && headerBytes+currentBytes+rowBytes > fp.MaxBytesPerFile
+		if exceedsRows || exceedsBytes {
+			flush()
+		}
+
+		current = append(current, errInfo)
+		currentBytes += rowBytes
+	}
+	flush()
+
+	if len(segments) == 0 {
+		// 没有命中任何错误时也生成一个空分段，保持与非滚动模式一致的“总有输出文件”行为
+		segments = append(segments, rollingSegment{path: newSegmentPath()})
+	}
+
+	return segments
+}
+
+// writeMarkdownSegment 把一个分段写成独立的 Markdown 文件，重新写入表头
+func (fp *FileProcessor) writeMarkdownSegment(path string, rows []ErrorInfo) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	defer writer.Flush()
+
+	return markdownFormatter{}.Write(writer, rows)
+}
+
+// writeRollingIndex 在 outputPath 生成索引文件，列出所有分段并汇总每个分段、
+// 每个源文件命中的错误数量，方便在大量分段下仍能定位感兴趣的内容
+func (fp *FileProcessor) writeRollingIndex(outputPath string, segments []rollingSegment) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	defer writer.Flush()
+
+	totalRows := 0
+	for _, seg := range segments {
+		totalRows += len(seg.rows)
+	}
+
+	if _, err := fmt.Fprintf(writer, "# 相关错误信息汇总（索引）\n\n共 %d 条错误，分为 %d 个分段文件：\n\n", totalRows, len(segments)); err != nil {
+		return err
+	}
+
+	for _, seg := range segments {
+		perFile := make(map[string]int)
+		var fileOrder []string
+		for _, row := range seg.rows {
+			if _, ok := perFile[row.FilePath]; !ok {
+				fileOrder = append(fileOrder, row.FilePath)
+			}
+			perFile[row.FilePath]++
+		}
+
+		if _, err := fmt.Fprintf(writer, "## [%s](%s)（%d 条）\n\n", filepath.Base(seg.path), filepath.Base(seg.path), len(seg.rows)); err != nil {
+			return err
+		}
+
+		for _, f := range fileOrder {
+			if _, err := fmt.Fprintf(writer, "- %s: %d 条\n", f, perFile[f]); err != nil {
+				return err
+			}
+		}
+		if _, err := writer.WriteString("\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sampleRollingErrors(n int) []ErrorInfo {
+	errs := make([]ErrorInfo, 0, n)
+	for i := 1; i <= n; i++ {
+		errs = append(errs, ErrorInfo{
+			Index:        i,
+			ErrorMessage: "boom",
+			FilePath:     "a.go",
+			LineNum:      i,
+		})
+	}
+	return errs
+}
+
+func TestSplitIntoSegmentsByRowCount(t *testing.T) {
+	fp := NewFileProcessor(t.TempDir())
+	fp.MaxRowsPerFile = 2
+
+	segments := fp.splitIntoSegments(filepath.Join(t.TempDir(), "out.md"), sampleRollingErrors(5))
+
+	if len(segments) != 3 {
+		t.Fatalf("expected 3 segments for 5 rows with MaxRowsPerFile=2, got %d", len(segments))
+	}
+	wantSizes := []int{2, 2, 1}
+	for i, seg := range segments {
+		if len(seg.rows) != wantSizes[i] {
+			t.Errorf("segment %d: expected %d rows, got %d", i, wantSizes[i], len(seg.rows))
+		}
+	}
+}
+
+func TestSplitIntoSegmentsByByteCount(t *testing.T) {
+	errs := sampleRollingErrors(1)
+	rowBytes := int64(len(markdownTableRow(errs[0])))
+	headerBytes := int64(len(markdownTableHeader()))
+
+	fp := NewFileProcessor(t.TempDir())
+	// 容量刚好装下 2 行，第 3 行必须另起一个分段
+	fp.MaxBytesPerFile = headerBytes + 2*rowBytes
+
+	segments := fp.splitIntoSegments(filepath.Join(t.TempDir(), "out.md"), sampleRollingErrors(5))
+
+	if len(segments) != 3 {
+		t.Fatalf("expected 3 segments, got %d", len(segments))
+	}
+	wantSizes := []int{2, 2, 1}
+	for i, seg := range segments {
+		if len(seg.rows) != wantSizes[i] {
+			t.Errorf("segment %d: expected %d rows, got %d", i, wantSizes[i], len(seg.rows))
+		}
+	}
+}
+
+func TestSplitIntoSegmentsEmptyResultStillProducesOneSegment(t *testing.T) {
+	fp := NewFileProcessor(t.TempDir())
+	fp.MaxRowsPerFile = 10
+
+	segments := fp.splitIntoSegments(filepath.Join(t.TempDir(), "out.md"), nil)
+
+	if len(segments) != 1 {
+		t.Fatalf("expected exactly 1 (empty) segment when there are no errors, got %d", len(segments))
+	}
+	if len(segments[0].rows) != 0 {
+		t.Fatalf("expected the single segment to have 0 rows, got %d", len(segments[0].rows))
+	}
+}
+
+func TestSplitIntoSegmentsSingleOversizedRowStillEmitted(t *testing.T) {
+	errs := sampleRollingErrors(1)
+	rowBytes := int64(len(markdownTableRow(errs[0])))
+
+	fp := NewFileProcessor(t.TempDir())
+	fp.MaxBytesPerFile = rowBytes // 比表头+一行还要小
+
+	segments := fp.splitIntoSegments(filepath.Join(t.TempDir(), "out.md"), sampleRollingErrors(2))
+
+	total := 0
+	for _, seg := range segments {
+		total += len(seg.rows)
+	}
+	if total != 2 {
+		t.Fatalf("expected all rows to still be emitted even if a single row exceeds MaxBytesPerFile, got %d", total)
+	}
+}
+
+func TestWriteRollingIndexSummarizesSegmentsAndFiles(t *testing.T) {
+	fp := NewFileProcessor(t.TempDir())
+	outputPath := filepath.Join(t.TempDir(), "out.md")
+
+	segments := []rollingSegment{
+		{path: outputPath[:len(outputPath)-3] + "_seg1.md", rows: []ErrorInfo{
+			{ErrorMessage: "e1", FilePath: "a.go", LineNum: 1},
+			{ErrorMessage: "e2", FilePath: "a.go", LineNum: 2},
+		}},
+		{path: outputPath[:len(outputPath)-3] + "_seg2.md", rows: []ErrorInfo{
+			{ErrorMessage: "e3", FilePath: "b.go", LineNum: 1},
+		}},
+	}
+
+	if err := fp.writeRollingIndex(outputPath, segments); err != nil {
+		t.Fatalf("writeRollingIndex returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read index file: %v", err)
+	}
+	out := string(data)
+
+	if !strings.Contains(out, "共 3 条错误，分为 2 个分段文件") {
+		t.Fatalf("expected index to summarize total count and segment count, got:\n%s", out)
+	}
+	if !strings.Contains(out, "a.go: 2 条") {
+		t.Fatalf("expected per-file count for a.go, got:\n%s", out)
+	}
+	if !strings.Contains(out, "b.go: 1 条") {
+		t.Fatalf("expected per-file count for b.go, got:\n%s", out)
+	}
+}
+
+func TestWriteRollingMarkdownEndToEnd(t *testing.T) {
+	fp := NewFileProcessor(t.TempDir())
+	fp.MaxRowsPerFile = 2
+	fp.Format = FormatMarkdown
+
+	outputPath := filepath.Join(t.TempDir(), "errors.md")
+	if err := fp.writeOutputFile(outputPath, sampleRollingErrors(5)); err != nil {
+		t.Fatalf("writeOutputFile returned error: %v", err)
+	}
+
+	indexData, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read index file: %v", err)
+	}
+	if !strings.Contains(string(indexData), "共 5 条错误，分为 3 个分段文件") {
+		t.Fatalf("expected index summary for 5 rows / 3 segments, got:\n%s", indexData)
+	}
+
+	dir := filepath.Dir(outputPath)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to list output dir: %v", err)
+	}
+	segmentFiles := 0
+	for _, e := range entries {
+		if e.Name() != filepath.Base(outputPath) && strings.HasSuffix(e.Name(), ".md") {
+			segmentFiles++
+		}
+	}
+	if segmentFiles != 3 {
+		t.Fatalf("expected 3 segment files on disk, found %d", segmentFiles)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// streamChunkLines 是流式扫描时相邻两个分块之间向前推进的行数；每次喂给
+// SearchErrors 的分块至多为 streamChunkLines+streamOverlapLines 行，
+// 用来在内存占用和跨行模式匹配能力之间取得平衡
+const streamChunkLines = 2000
+
+// streamOverlapLines 是相邻分块之间保留的重叠行数。任何跨越分块边界、
+// 但整体不超过这个行数的多行错误/堆栈模式，都会完整落在至少一个分块里
+// 从而被正确匹配；重叠区域产生的重复匹配在 flush 阶段按行号+消息去重。
+const streamOverlapLines = 200
+
+// SearchErrorsStream 逐行扫描 r 中的内容并搜索错误信息，适用于单个文件大到
+// 不适合用 os.ReadFile 整体加载的场景（例如生成的代码或被提交的 vendor 目录）。
+// 扫描按 streamChunkLines 分块、并在分块之间保留 streamOverlapLines 行重叠
+// 喂给 SearchErrors，每个分块返回的 LineNum 会按已经越过的行数做偏移，
+// 以保证全局行号与整体读取时一致；重叠区域内重复命中的结果会被去重——去重
+// 只针对"上一个分块也命中过的同一行号+消息"，不会跨整个文件去重，因此同一行
+// 内出现的两个相同错误消息（SearchErrors 对单行做 FindAllString 之类的场景）
+// 依然会被各自保留。
+func (s *ErrorSearcher) SearchErrorsStream(r io.Reader, path string) ([]ErrorInfo, error) {
+	return searchErrorsStream(r, path, s.SearchErrors)
+}
+
+// searchErrorsStream 是 SearchErrorsStream 的核心实现，search 对应被注入的
+// 单分块搜索函数（生产环境中始终是 ErrorSearcher.SearchErrors）；拆出这一层
+// 是为了能在测试里注入一个可控的假搜索函数，覆盖重叠去重逻辑而不必依赖
+// 真正的 ErrorSearcher 匹配规则。
+func searchErrorsStream(r io.Reader, path string, search func(content, path string) []ErrorInfo) ([]ErrorInfo, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 8*1024*1024)
+
+	var allErrors []ErrorInfo
+	var lines []string
+	lineOffset := 0
+	var prevKeys map[string]bool
+
+	flush := func(final bool) {
+		if len(lines) == 0 {
+			return
+		}
+
+		errs := search(strings.Join(lines, "\n")+"\n", path)
+		currentKeys := make(map[string]bool, len(errs))
+		for _, errInfo := range errs {
+			errInfo.LineNum += lineOffset
+			key := fmt.Sprintf("%d:%s", errInfo.LineNum, errInfo.ErrorMessage)
+			if prevKeys[key] {
+				// 上一个分块的重叠区域已经报告过同一行号+消息的匹配，跳过
+				continue
+			}
+			currentKeys[key] = true
+			allErrors = append(allErrors, errInfo)
+		}
+		prevKeys = currentKeys
+
+		if final {
+			lines = nil
+			return
+		}
+
+		// 保留分块尾部的重叠行作为下一分块的开头，其余行正式计入 lineOffset
+		keep := streamOverlapLines
+		if keep > len(lines) {
+			keep = len(lines)
+		}
+		lineOffset += len(lines) - keep
+		lines = append([]string(nil), lines[len(lines)-keep:]...)
+	}
+
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) >= streamChunkLines+streamOverlapLines {
+			flush(false)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("流式扫描文件失败: %w", err)
+	}
+	flush(true)
+
+	return allErrors, nil
+}
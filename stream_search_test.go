@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// fakeLineSearch 模拟一个真实的 SearchErrors：逐行扫描并在命中标记时产出
+// ErrorInfo，支持同一行产出多条相同消息的匹配（FindAllString 场景）。
+func fakeLineSearch(content, path string) []ErrorInfo {
+	var out []ErrorInfo
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		switch line {
+		case "BOUNDARY":
+			out = append(out, ErrorInfo{ErrorMessage: "boundary-hit", FilePath: path, LineNum: i + 1})
+		case "DUPDUP":
+			out = append(out, ErrorInfo{ErrorMessage: "dup-hit", FilePath: path, LineNum: i + 1})
+			out = append(out, ErrorInfo{ErrorMessage: "dup-hit", FilePath: path, LineNum: i + 1})
+		}
+	}
+	return out
+}
+
+func countMatches(errs []ErrorInfo, message string) int {
+	count := 0
+	for _, e := range errs {
+		if e.ErrorMessage == message {
+			count++
+		}
+	}
+	return count
+}
+
+// TestSearchErrorsStreamDedupsOnlyAcrossChunkBoundary 验证一个恰好落在两个
+// 分块重叠区域里的匹配只会被报告一次（且行号正确），而不会被整体去重逻辑
+// 意外吞掉其他匹配。
+func TestSearchErrorsStreamDedupsOnlyAcrossChunkBoundary(t *testing.T) {
+	const boundaryLine = 2150 // 落在第一个分块的重叠区域（2001-2200）内
+
+	totalLines := streamChunkLines + streamOverlapLines + 50
+	lines := make([]string, totalLines)
+	for i := range lines {
+		lines[i] = "package main"
+	}
+	lines[boundaryLine-1] = "BOUNDARY"
+
+	reader := strings.NewReader(strings.Join(lines, "\n") + "\n")
+
+	errs, err := searchErrorsStream(reader, "big.go", fakeLineSearch)
+	if err != nil {
+		t.Fatalf("searchErrorsStream returned error: %v", err)
+	}
+
+	if got := countMatches(errs, "boundary-hit"); got != 1 {
+		t.Fatalf("expected exactly 1 boundary-hit match, got %d", got)
+	}
+
+	for _, e := range errs {
+		if e.ErrorMessage == "boundary-hit" && e.LineNum != boundaryLine {
+			t.Fatalf("expected boundary-hit at line %d, got %d", boundaryLine, e.LineNum)
+		}
+	}
+}
+
+// TestSearchErrorsStreamKeepsGenuineSameLineDuplicates 验证同一行内出现的
+// 两个相同错误消息不会被去重逻辑误判成跨分块重复而丢弃一个。
+func TestSearchErrorsStreamKeepsGenuineSameLineDuplicates(t *testing.T) {
+	content := "package main\nDUPDUP\nfunc f() {}\n"
+	reader := strings.NewReader(content)
+
+	errs, err := searchErrorsStream(reader, "small.go", fakeLineSearch)
+	if err != nil {
+		t.Fatalf("searchErrorsStream returned error: %v", err)
+	}
+
+	if got := countMatches(errs, "dup-hit"); got != 2 {
+		t.Fatalf("expected both same-line duplicate matches to survive, got %d", got)
+	}
+}
+
+// TestSearchErrorsStreamMatchesWholeFileSearch 确认对于不依赖分块/重叠的
+// 普通输入，流式扫描和一次性扫描返回完全相同的结果。
+func TestSearchErrorsStreamMatchesWholeFileSearch(t *testing.T) {
+	content := "package main\nDUPDUP\nBOUNDARY\n"
+
+	whole := fakeLineSearch(content, "whole.go")
+	streamed, err := searchErrorsStream(strings.NewReader(content), "whole.go", fakeLineSearch)
+	if err != nil {
+		t.Fatalf("searchErrorsStream returned error: %v", err)
+	}
+
+	if len(whole) != len(streamed) {
+		t.Fatalf("expected streaming and whole-file paths to agree on match count: whole=%d streamed=%d", len(whole), len(streamed))
+	}
+	for i := range whole {
+		if whole[i].ErrorMessage != streamed[i].ErrorMessage || whole[i].FilePath != streamed[i].FilePath || whole[i].LineNum != streamed[i].LineNum {
+			t.Fatalf("match %d differs: whole=%+v streamed=%+v", i, whole[i], streamed[i])
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// watchPollInterval 是轮询检测文件变化的间隔。没有引入 fsnotify 依赖，
+// 用 os.Stat 的 ModTime/Size 做轮询在单文件工具里足够简单可靠。
+const watchPollInterval = 2 * time.Second
+
+// watchRacyWindow 借鉴 git 索引的 "racily clean" 处理方式：如果文件的
+// ModTime 离现在足够久，mtime/size 相同就足以确认内容没变；但如果文件是
+// 刚刚（在本轮轮询间隔量级内）被修改的，mtime 的秒级精度可能无法区分
+// "同一秒内发生的两次写入"，这时要用 SHA256 再做一次确认，而不是盲目信任
+// mtime/size。
+const watchRacyWindow = 2 * time.Second
+
+// cacheEntry 缓存单个文件上一次扫描时的元数据和结果，文件未变化时可以直接复用
+type cacheEntry struct {
+	ModTime time.Time   `json:"mod_time"`
+	Size    int64       `json:"size"`
+	SHA256  string      `json:"sha256"`
+	Errors  []ErrorInfo `json:"errors"`
+}
+
+// watchCache 是持久化到磁盘的缓存，key 为相对 baseDir 的文件路径
+type watchCache map[string]cacheEntry
+
+// watchCachePath 返回与 outputPath 配套的缓存文件路径
+func watchCachePath(outputPath string) string {
+	return outputPath + ".cache.json"
+}
+
+// loadWatchCache 读取 outputPath 对应的缓存文件；文件不存在时返回空缓存
+func loadWatchCache(outputPath string) (watchCache, error) {
+	data, err := os.ReadFile(watchCachePath(outputPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return watchCache{}, nil
+		}
+		return nil, err
+	}
+
+	cache := watchCache{}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+// saveWatchCache 把缓存写回磁盘
+func saveWatchCache(outputPath string, cache watchCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(watchCachePath(outputPath), data, 0o644)
+}
+
+// cacheHit 判断 entry 是否仍然代表 path 的当前内容。mtime/size 不一致时
+// 直接判定为未命中；一致但文件处于 watchRacyWindow 之内时，再用 SHA256
+// 做一次确认，避免粗粒度 mtime 掩盖同一秒内的二次写入。
+func (fp *FileProcessor) cacheHit(path string, info os.FileInfo, entry cacheEntry) bool {
+	if !entry.ModTime.Equal(info.ModTime()) || entry.Size != info.Size() {
+		return false
+	}
+	if time.Since(info.ModTime()) >= watchRacyWindow {
+		return true
+	}
+
+	sum, err := fileSHA256(path)
+	return err == nil && sum == entry.SHA256
+}
+
+// fileSHA256 计算文件内容的 SHA256，用于 cacheHit 的二次确认
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// Watch 以轮询方式监视 fp.baseDir 下的 .go 文件变化，只重新扫描 ModTime/Size
+// 发生变化的文件，其余文件复用磁盘缓存中的结果，每一轮都会重新生成 outputPath。
+// ctx 被取消时返回 ctx.Err()。
+func (fp *FileProcessor) Watch(ctx context.Context, outputPath string, searcher *ErrorSearcher) error {
+	cache, err := loadWatchCache(outputPath)
+	if err != nil {
+		return fmt.Errorf("读取增量缓存失败: %w", err)
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := fp.runWatchPass(outputPath, searcher, cache); err != nil {
+			fmt.Printf("增量扫描失败: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// runWatchPass 扫描一轮：跳过缓存里未变化的文件，重新扫描变化过的文件，
+// 把汇总结果写入 outputPath，并刷新、裁剪磁盘缓存
+func (fp *FileProcessor) runWatchPass(outputPath string, searcher errorSearcher, cache watchCache) error {
+	var allErrors []ErrorInfo
+	seen := make(map[string]bool)
+
+	err := filepath.WalkDir(fp.baseDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if fp.isExcluded(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(strings.ToLower(path), ".go") || fp.isExcluded(path) || !fp.isIncluded(path) {
+			return nil
+		}
+
+		relPath, relErr := filepath.Rel(fp.baseDir, path)
+		if relErr != nil {
+			relPath = path
+		}
+		seen[relPath] = true
+
+		info, statErr := d.Info()
+		if statErr != nil {
+			return statErr
+		}
+
+		if entry, ok := cache[relPath]; ok && fp.cacheHit(path, info, entry) {
+			allErrors = append(allErrors, entry.Errors...)
+			return nil
+		}
+
+		content, readErr := fp.readFileContent(path)
+		if readErr != nil {
+			fmt.Printf("读取文件时出错 %s: %v\n", relPath, readErr)
+			return nil
+		}
+
+		errors := searcher.SearchErrors(content, relPath)
+		sum := sha256.Sum256([]byte(content))
+		cache[relPath] = cacheEntry{
+			ModTime: info.ModTime(),
+			Size:    info.Size(),
+			SHA256:  hex.EncodeToString(sum[:]),
+			Errors:  errors,
+		}
+		allErrors = append(allErrors, errors...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("扫描目录失败: %w", err)
+	}
+
+	// 清理已被删除文件留下的缓存条目
+	for relPath := range cache {
+		if !seen[relPath] {
+			delete(cache, relPath)
+		}
+	}
+
+	// Index 必须全局单调，每轮都要重新编号
+	for i := range allErrors {
+		allErrors[i].Index = i + 1
+	}
+
+	if err := fp.writeOutputFile(outputPath, allErrors); err != nil {
+		return fmt.Errorf("写入输出文件失败: %w", err)
+	}
+
+	return saveWatchCache(outputPath, cache)
+}
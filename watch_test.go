@@ -0,0 +1,190 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// countingSearcher 包装 fakeSearcher，记录 SearchErrors 被调用的次数，
+// 用来验证 runWatchPass 是否真的跳过了缓存命中的文件
+type countingSearcher struct {
+	*fakeSearcher
+	calls *int
+}
+
+func (c *countingSearcher) SearchErrors(content, path string) []ErrorInfo {
+	*c.calls++
+	return c.fakeSearcher.SearchErrors(content, path)
+}
+
+func TestCacheHitRejectsMismatchedStat(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fp := NewFileProcessor(dir)
+	entry := cacheEntry{ModTime: info.ModTime().Add(-time.Second), Size: info.Size()}
+	if fp.cacheHit(path, info, entry) {
+		t.Fatal("expected cacheHit to reject an entry with a different ModTime")
+	}
+}
+
+func TestCacheHitTrustsOldMatchingStatWithoutHashing(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "old.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	past := time.Now().Add(-10 * time.Minute)
+	if err := os.Chtimes(path, past, past); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 哈希故意写错，验证远离 racy window 时 cacheHit 不会重新计算哈希
+	entry := cacheEntry{ModTime: info.ModTime(), Size: info.Size(), SHA256: "deliberately-wrong-hash"}
+
+	fp := NewFileProcessor(dir)
+	if !fp.cacheHit(path, info, entry) {
+		t.Fatal("expected cacheHit to trust mtime/size outside the racy window even with a mismatched hash")
+	}
+}
+
+func TestCacheHitRehashesWithinRacyWindow(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "recent.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// entry 的 mtime/size 和当前文件一致，但 hash 对应的是旧内容——
+	// 模拟编辑器在同一秒内重写文件的场景
+	entry := cacheEntry{ModTime: info.ModTime(), Size: info.Size(), SHA256: "stale-hash-from-previous-content"}
+
+	fp := NewFileProcessor(dir)
+	if fp.cacheHit(path, info, entry) {
+		t.Fatal("expected cacheHit to reject a stale hash for a recently modified file")
+	}
+}
+
+func TestCacheHitAcceptsMatchingHashWithinRacyWindow(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "recent.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := fileSHA256(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := cacheEntry{ModTime: info.ModTime(), Size: info.Size(), SHA256: sum}
+
+	fp := NewFileProcessor(dir)
+	if !fp.cacheHit(path, info, entry) {
+		t.Fatal("expected cacheHit to accept a matching hash within the racy window")
+	}
+}
+
+func TestRunWatchPassSkipsUnchangedAndRescansModified(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fp := NewFileProcessor(dir)
+	outputPath := filepath.Join(t.TempDir(), "out.md")
+
+	calls := 0
+	searcher := &countingSearcher{
+		fakeSearcher: &fakeSearcher{errorsByPath: map[string][]ErrorInfo{
+			"a.go": {{ErrorMessage: "boom", FilePath: "a.go", LineNum: 1}},
+		}},
+		calls: &calls,
+	}
+
+	cache := watchCache{}
+	if err := fp.runWatchPass(outputPath, searcher, cache); err != nil {
+		t.Fatalf("first pass failed: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 scan after first pass, got %d", calls)
+	}
+
+	if err := fp.runWatchPass(outputPath, searcher, cache); err != nil {
+		t.Fatalf("second pass failed: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected cache hit to avoid rescanning an unchanged file, got %d total calls", calls)
+	}
+
+	searcher.errorsByPath["a.go"] = []ErrorInfo{{ErrorMessage: "changed", FilePath: "a.go", LineNum: 2}}
+	if err := os.WriteFile(path, []byte("package main\n\nfunc f() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fp.runWatchPass(outputPath, searcher, cache); err != nil {
+		t.Fatalf("third pass failed: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected modified file to be rescanned, got %d total calls", calls)
+	}
+}
+
+func TestRunWatchPassPrunesDeletedFilesFromCache(t *testing.T) {
+	dir := t.TempDir()
+	keepPath := filepath.Join(dir, "keep.go")
+	goAwayPath := filepath.Join(dir, "gone.go")
+	if err := os.WriteFile(keepPath, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(goAwayPath, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fp := NewFileProcessor(dir)
+	outputPath := filepath.Join(t.TempDir(), "out.md")
+	calls := 0
+	searcher := &countingSearcher{fakeSearcher: &fakeSearcher{errorsByPath: map[string][]ErrorInfo{}}, calls: &calls}
+
+	cache := watchCache{}
+	if err := fp.runWatchPass(outputPath, searcher, cache); err != nil {
+		t.Fatalf("first pass failed: %v", err)
+	}
+	if _, ok := cache["gone.go"]; !ok {
+		t.Fatal("expected cache to contain an entry for gone.go after the first pass")
+	}
+
+	if err := os.Remove(goAwayPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := fp.runWatchPass(outputPath, searcher, cache); err != nil {
+		t.Fatalf("second pass failed: %v", err)
+	}
+	if _, ok := cache["gone.go"]; ok {
+		t.Fatal("expected cache entry for a deleted file to be pruned")
+	}
+	if _, ok := cache["keep.go"]; !ok {
+		t.Fatal("expected cache entry for an untouched file to survive pruning")
+	}
+}